@@ -0,0 +1,243 @@
+package database
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/azidentity"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/blob"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/bloberror"
+)
+
+// AzureConfig carries the connection details needed to build an *azblob.Client for Azure Blob Storage.
+// When AccountKey is empty, the client falls back to azidentity.DefaultAzureCredential (environment
+// variables, managed identity, Azure CLI login), mirroring S3Config's "env_auth" behavior.
+type AzureConfig struct {
+	// AccountName is the storage account to connect to, e.g. "mystorageaccount".
+	AccountName string
+	// AccountKey is a shared key credential. Leave empty (with AccountName still set) to use
+	// DefaultAzureCredential instead.
+	AccountKey string
+	// ServiceURL overrides the derived "https://<account>.blob.core.windows.net" endpoint, for the
+	// Azurite emulator or Azure Stack.
+	ServiceURL string
+}
+
+// AzureEncryptionConfig selects the server-side encryption AzureDatabaseWriter applies to blobs it writes.
+type AzureEncryptionConfig struct {
+	// CustomerKey is a 256-bit (32 byte) customer-provided key (CPK). Leave nil to use Microsoft-managed
+	// keys.
+	CustomerKey []byte
+}
+
+// AzureDatabaseWriter is a BlobStore that stores the database in a target Azure Blob Storage container.
+type AzureDatabaseWriter struct {
+	client     *azblob.Client
+	container  string
+	prefix     string
+	encryption AzureEncryptionConfig
+	verbose    bool
+}
+
+var (
+	_ BlobStore          = (*AzureDatabaseWriter)(nil)
+	_ LastModifiedGetter = (*AzureDatabaseWriter)(nil)
+)
+
+// AzureWriterOption configures optional behavior on an AzureDatabaseWriter that most callers don't need to
+// set explicitly.
+type AzureWriterOption func(*AzureDatabaseWriter)
+
+// WithAzurePrefix stores editions under keyPrefix within the container, matching the "prefix" segment of
+// an "azblob://container/prefix" destination URL.
+func WithAzurePrefix(keyPrefix string) AzureWriterOption {
+	return func(writer *AzureDatabaseWriter) {
+		writer.prefix = keyPrefix
+	}
+}
+
+// NewAzureDatabaseWriter creates a new AzureDatabaseWriter around an existing *azblob.Client.
+func NewAzureDatabaseWriter(
+	client *azblob.Client,
+	container string,
+	verbose bool,
+	encryption AzureEncryptionConfig,
+	opts ...AzureWriterOption,
+) (*AzureDatabaseWriter, error) {
+	dbWriter := &AzureDatabaseWriter{
+		client:     client,
+		container:  container,
+		encryption: encryption,
+		verbose:    verbose,
+	}
+
+	for _, opt := range opts {
+		opt(dbWriter)
+	}
+
+	return dbWriter, nil
+}
+
+// NewAzureDatabaseWriterFromConfig builds the underlying *azblob.Client from azureConfig and returns a new
+// AzureDatabaseWriter.
+func NewAzureDatabaseWriterFromConfig(
+	azureConfig AzureConfig,
+	container string,
+	verbose bool,
+	encryption AzureEncryptionConfig,
+	opts ...AzureWriterOption,
+) (*AzureDatabaseWriter, error) {
+	serviceURL := azureConfig.ServiceURL
+	if serviceURL == "" {
+		serviceURL = fmt.Sprintf("https://%s.blob.core.windows.net/", azureConfig.AccountName)
+	}
+
+	client, err := newAzureClient(serviceURL, azureConfig)
+	if err != nil {
+		return nil, fmt.Errorf("building Azure Blob Storage client: %w", err)
+	}
+
+	return NewAzureDatabaseWriter(client, container, verbose, encryption, opts...)
+}
+
+func newAzureClient(serviceURL string, azureConfig AzureConfig) (*azblob.Client, error) {
+	if azureConfig.AccountKey != "" {
+		cred, err := azblob.NewSharedKeyCredential(azureConfig.AccountName, azureConfig.AccountKey)
+		if err != nil {
+			return nil, fmt.Errorf("building shared key credential: %w", err)
+		}
+
+		return azblob.NewClientWithSharedKeyCredential(serviceURL, cred, nil)
+	}
+
+	cred, err := azidentity.NewDefaultAzureCredential(nil)
+	if err != nil {
+		return nil, fmt.Errorf("building default Azure credential: %w", err)
+	}
+
+	return azblob.NewClient(serviceURL, cred, nil)
+}
+
+func (writer *AzureDatabaseWriter) getObjectKey(editionID string) string {
+	if writer.prefix == "" {
+		return editionID + extension
+	}
+
+	return writer.prefix + "/" + editionID + extension
+}
+
+// cpkOptions returns the customer-provided key (CPK) server-side encryption options matching
+// writer.encryption, or nil for Microsoft-managed keys, shared between
+// GetHash/GetLastModified (GetProperties) and Write (UploadStream).
+func (writer *AzureDatabaseWriter) cpkOptions() *blob.CPKInfo {
+	if len(writer.encryption.CustomerKey) == 0 {
+		return nil
+	}
+
+	keySHA256 := sha256.Sum256(writer.encryption.CustomerKey)
+
+	return &blob.CPKInfo{
+		EncryptionKey:       to.Ptr(base64.StdEncoding.EncodeToString(writer.encryption.CustomerKey)),
+		EncryptionKeySHA256: to.Ptr(base64.StdEncoding.EncodeToString(keySHA256[:])),
+		EncryptionAlgorithm: to.Ptr(blob.EncryptionAlgorithmTypeAES256),
+	}
+}
+
+// GetHash returns the Content-MD5 of editionID's current blob, read from its properties, or ZeroMD5 if the
+// blob doesn't exist yet.
+func (writer *AzureDatabaseWriter) GetHash(editionID string) (string, error) {
+	ctx := context.TODO()
+	objectKey := writer.getObjectKey(editionID)
+
+	blobClient := writer.client.ServiceClient().NewContainerClient(writer.container).NewBlobClient(objectKey)
+
+	props, err := blobClient.GetProperties(ctx, &blob.GetPropertiesOptions{CPKInfo: writer.cpkOptions()})
+	if err != nil {
+		if bloberror.HasCode(err, bloberror.BlobNotFound) {
+			return ZeroMD5, nil
+		}
+
+		return "", fmt.Errorf("getting properties for %s in container %s: %w", objectKey, writer.container, err)
+	}
+
+	return hex.EncodeToString(props.ContentMD5), nil
+}
+
+// GetLastModified reads back the MaxMind source modification date recorded in the DateModifiedTag blob
+// metadata key when the edition was last written, rather than relying on Azure's LastModified, which
+// changes whenever the blob's properties or tier are updated.
+func (writer *AzureDatabaseWriter) GetLastModified(editionID string) (time.Time, error) {
+	ctx := context.TODO()
+	objectKey := writer.getObjectKey(editionID)
+
+	blobClient := writer.client.ServiceClient().NewContainerClient(writer.container).NewBlobClient(objectKey)
+
+	props, err := blobClient.GetProperties(ctx, &blob.GetPropertiesOptions{CPKInfo: writer.cpkOptions()})
+	if err != nil {
+		if bloberror.HasCode(err, bloberror.BlobNotFound) {
+			return time.Time{}, nil
+		}
+
+		return time.Time{}, fmt.Errorf("getting properties for %s in container %s: %w", objectKey, writer.container, err)
+	}
+
+	raw, ok := props.Metadata[DateModifiedTag]
+	if !ok || raw == nil {
+		return time.Time{}, nil
+	}
+
+	lastModified, err := time.Parse(time.RFC3339, *raw)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("parsing %s metadata for %s: %w", DateModifiedTag, objectKey, err)
+	}
+
+	return lastModified, nil
+}
+
+// Write streams reader directly into an Azure blob, validating its MD5 on the fly via the same
+// hashValidatingReader the S3 backend uses.
+func (writer *AzureDatabaseWriter) Write(
+	editionID string,
+	reader io.ReadCloser,
+	newMD5 string,
+	lastModified time.Time,
+) (err error) {
+	defer func() {
+		_, _ = io.Copy(io.Discard, reader) //nolint:errcheck // Best effort.
+		if closeErr := reader.Close(); closeErr != nil {
+			err = errors.Join(err, fmt.Errorf("closing reader for %s: %w", editionID, closeErr))
+		}
+	}()
+
+	ctx := context.TODO()
+	objectKey := writer.getObjectKey(editionID)
+
+	_, uploadErr := writer.client.UploadStream(ctx, writer.container, objectKey, newHashValidatingReader(reader, newMD5),
+		&azblob.UploadStreamOptions{
+			Metadata: map[string]*string{
+				DateModifiedTag: to.Ptr(lastModified.UTC().Format(time.RFC3339)),
+			},
+			CPKInfo: writer.cpkOptions(),
+		})
+	if uploadErr != nil {
+		if errors.Is(uploadErr, errHashMismatch) {
+			return fmt.Errorf("validating hash for %s: %w", editionID, uploadErr)
+		}
+
+		return fmt.Errorf("writing %s to Azure Blob Storage: %w", editionID, uploadErr)
+	}
+
+	if writer.verbose {
+		log.Printf("Database %s successfully updated: %+v", editionID, newMD5)
+	}
+	return nil
+}
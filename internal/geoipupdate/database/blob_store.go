@@ -0,0 +1,60 @@
+package database
+
+import (
+	"fmt"
+	"io"
+	"time"
+)
+
+// BlobStore is the minimal interface an object storage backend must satisfy to receive GeoIP database
+// updates. NewBlobStoreFromURL selects an implementation by the scheme of the configured destination URL
+// (s3://, gs://, or azblob://); a plain "file://" destination has no BlobStore implementation and is
+// handled separately by the caller.
+type BlobStore interface {
+	// GetHash returns the backend-native hash for editionID's current object, or ZeroMD5 if it doesn't
+	// exist yet.
+	GetHash(editionID string) (string, error)
+
+	// Write uploads the content of reader as editionID's new object, validating that it hashes to newMD5
+	// and recording lastModified as the upstream MaxMind modification date.
+	Write(editionID string, reader io.ReadCloser, newMD5 string, lastModified time.Time) error
+}
+
+// LastModifiedGetter is implemented by BlobStore backends that can report back the MaxMind source
+// modification date recorded at write time, independently of the backend's own last-modified timestamp
+// (which a copy, re-encryption, or lifecycle transition can reset). The update decision path prefers this
+// over GetHash alone when a backend supports it.
+type LastModifiedGetter interface {
+	GetLastModified(editionID string) (time.Time, error)
+}
+
+var (
+	_ BlobStore          = (*S3DatabaseWriter)(nil)
+	_ LastModifiedGetter = (*S3DatabaseWriter)(nil)
+)
+
+// NeedsUpdate reports whether editionID should be re-uploaded to store. When store also implements
+// LastModifiedGetter, the decision is made by comparing sourceModified (the modification date MaxMind
+// reported for this download) against the date recorded at the last write, since that survives a copy or
+// re-encryption that would reset the backend's own last-modified timestamp. Backends that don't implement
+// LastModifiedGetter, or that have never recorded a source date for this edition, fall back to comparing
+// newMD5 against GetHash.
+func NeedsUpdate(store BlobStore, editionID string, newMD5 string, sourceModified time.Time) (bool, error) {
+	if getter, ok := store.(LastModifiedGetter); ok {
+		lastModified, err := getter.GetLastModified(editionID)
+		if err != nil {
+			return false, fmt.Errorf("getting last modified for %s: %w", editionID, err)
+		}
+
+		if !lastModified.IsZero() {
+			return sourceModified.After(lastModified), nil
+		}
+	}
+
+	currentHash, err := store.GetHash(editionID)
+	if err != nil {
+		return false, fmt.Errorf("getting hash for %s: %w", editionID, err)
+	}
+
+	return currentHash != newMD5, nil
+}
@@ -0,0 +1,82 @@
+package database
+
+import (
+	"io"
+	"testing"
+	"time"
+)
+
+type fakeBlobStore struct {
+	hash string
+}
+
+func (f *fakeBlobStore) GetHash(string) (string, error) { return f.hash, nil }
+
+func (f *fakeBlobStore) Write(string, io.ReadCloser, string, time.Time) error { return nil }
+
+type fakeBlobStoreWithLastModified struct {
+	fakeBlobStore
+	lastModified time.Time
+}
+
+func (f *fakeBlobStoreWithLastModified) GetLastModified(string) (time.Time, error) {
+	return f.lastModified, nil
+}
+
+func TestNeedsUpdate_FallsBackToHashWithoutLastModifiedGetter(t *testing.T) {
+	store := &fakeBlobStore{hash: "aaa"}
+
+	needsUpdate, err := NeedsUpdate(store, "GeoLite2-City", "bbb", time.Now())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !needsUpdate {
+		t.Fatal("expected an update when the stored hash differs from the new hash")
+	}
+
+	needsUpdate, err = NeedsUpdate(store, "GeoLite2-City", "aaa", time.Now())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if needsUpdate {
+		t.Fatal("expected no update when the stored hash matches the new hash")
+	}
+}
+
+func TestNeedsUpdate_FallsBackToHashWhenNoDateRecorded(t *testing.T) {
+	store := &fakeBlobStoreWithLastModified{fakeBlobStore: fakeBlobStore{hash: "aaa"}}
+
+	needsUpdate, err := NeedsUpdate(store, "GeoLite2-City", "aaa", time.Now())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if needsUpdate {
+		t.Fatal("expected no update when the hash matches and no source date was ever recorded")
+	}
+}
+
+func TestNeedsUpdate_PrefersRecordedSourceDateOverHash(t *testing.T) {
+	recorded := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	store := &fakeBlobStoreWithLastModified{
+		fakeBlobStore: fakeBlobStore{hash: "same-hash-despite-re-encryption"},
+		lastModified:  recorded,
+	}
+
+	newer := recorded.Add(24 * time.Hour)
+	needsUpdate, err := NeedsUpdate(store, "GeoLite2-City", "same-hash-despite-re-encryption", newer)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !needsUpdate {
+		t.Fatal("expected an update when the source modification date has advanced, even if the hash matches")
+	}
+
+	older := recorded.Add(-24 * time.Hour)
+	needsUpdate, err = NeedsUpdate(store, "GeoLite2-City", "same-hash-despite-re-encryption", older)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if needsUpdate {
+		t.Fatal("expected no update when the source modification date has not advanced")
+	}
+}
@@ -0,0 +1,82 @@
+package database
+
+import (
+	"bytes"
+	"encoding/base64"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+)
+
+func TestSSECustomerHeaders_RejectsWrongKeyLength(t *testing.T) {
+	for _, size := range []int{0, 16, 31, 33, 64} {
+		if _, _, _, err := sseCustomerHeaders(make([]byte, size)); err == nil {
+			t.Errorf("sseCustomerHeaders with a %d byte key: expected an error, got none", size)
+		}
+	}
+}
+
+func TestSSECustomerHeaders_EncodesKeyAndItsMD5(t *testing.T) {
+	key := bytes.Repeat([]byte{0x2a}, 32)
+
+	algorithm, base64Key, base64KeyMD5, err := sseCustomerHeaders(key)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if algorithm != "AES256" {
+		t.Errorf("algorithm = %q, want AES256", algorithm)
+	}
+
+	decodedKey, err := base64.StdEncoding.DecodeString(base64Key)
+	if err != nil {
+		t.Fatalf("decoding returned key: %v", err)
+	}
+	if !bytes.Equal(decodedKey, key) {
+		t.Errorf("decoded key = %x, want %x", decodedKey, key)
+	}
+
+	decodedKeyMD5, err := base64.StdEncoding.DecodeString(base64KeyMD5)
+	if err != nil {
+		t.Fatalf("decoding returned key MD5: %v", err)
+	}
+	if len(decodedKeyMD5) != 16 {
+		t.Errorf("decoded key MD5 length = %d, want 16", len(decodedKeyMD5))
+	}
+}
+
+func TestEncryptionConfig_ApplyToPut(t *testing.T) {
+	t.Run("SSE-C propagates the key error", func(t *testing.T) {
+		enc := EncryptionConfig{Mode: EncryptionSSEC, SSECustomerKey: []byte("too-short")}
+		if err := enc.applyToPut(&s3.PutObjectInput{}); err == nil {
+			t.Fatal("expected an error for an invalid SSE-C key")
+		}
+	})
+
+	t.Run("unknown mode is rejected", func(t *testing.T) {
+		enc := EncryptionConfig{Mode: "not-a-real-mode"}
+		if err := enc.applyToPut(&s3.PutObjectInput{}); err == nil {
+			t.Fatal("expected an error for an unknown encryption mode")
+		}
+	})
+
+	t.Run("SSE-C sets the customer key headers", func(t *testing.T) {
+		key := bytes.Repeat([]byte{0x11}, 32)
+		enc := EncryptionConfig{Mode: EncryptionSSEC, SSECustomerKey: key}
+
+		input := &s3.PutObjectInput{}
+		if err := enc.applyToPut(input); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if input.SSECustomerAlgorithm == nil || *input.SSECustomerAlgorithm != "AES256" {
+			t.Errorf("SSECustomerAlgorithm = %v, want AES256", input.SSECustomerAlgorithm)
+		}
+		if input.SSECustomerKey == nil || *input.SSECustomerKey == "" {
+			t.Error("SSECustomerKey was not set")
+		}
+		if input.SSECustomerKeyMD5 == nil || *input.SSECustomerKeyMD5 == "" {
+			t.Error("SSECustomerKeyMD5 was not set")
+		}
+	})
+}
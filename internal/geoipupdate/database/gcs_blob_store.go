@@ -0,0 +1,210 @@
+package database
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"time"
+
+	"cloud.google.com/go/storage"
+	"google.golang.org/api/option"
+)
+
+// GCSConfig carries the connection details needed to build a *storage.Client for Google Cloud Storage.
+// When CredentialsFile is empty, the client falls back to Application Default Credentials (environment
+// variable, gcloud config, or the GCE/GKE metadata server), mirroring S3Config's "env_auth" behavior.
+type GCSConfig struct {
+	// CredentialsFile is a path to a service account JSON key file. Leave empty to use Application
+	// Default Credentials.
+	CredentialsFile string
+}
+
+// GCSEncryptionConfig selects the server-side encryption GCSDatabaseWriter applies to objects it writes.
+type GCSEncryptionConfig struct {
+	// KMSKeyName is a Cloud KMS key resource name, e.g.
+	// "projects/p/locations/l/keyRings/r/cryptoKeys/k". Leave empty to use Google-managed encryption.
+	KMSKeyName string
+}
+
+// GCSDatabaseWriter is a BlobStore that stores the database in a target Google Cloud Storage bucket.
+type GCSDatabaseWriter struct {
+	client     *storage.Client
+	bucket     string
+	prefix     string
+	encryption GCSEncryptionConfig
+	verbose    bool
+}
+
+var (
+	_ BlobStore          = (*GCSDatabaseWriter)(nil)
+	_ LastModifiedGetter = (*GCSDatabaseWriter)(nil)
+)
+
+// GCSWriterOption configures optional behavior on a GCSDatabaseWriter that most callers don't need to set
+// explicitly.
+type GCSWriterOption func(*GCSDatabaseWriter)
+
+// WithGCSPrefix stores editions under keyPrefix within the bucket, matching the "prefix" segment of a
+// "gs://bucket/prefix" destination URL.
+func WithGCSPrefix(keyPrefix string) GCSWriterOption {
+	return func(writer *GCSDatabaseWriter) {
+		writer.prefix = keyPrefix
+	}
+}
+
+// NewGCSDatabaseWriter creates a new GCSDatabaseWriter around an existing *storage.Client.
+func NewGCSDatabaseWriter(
+	client *storage.Client,
+	bucket string,
+	verbose bool,
+	encryption GCSEncryptionConfig,
+	opts ...GCSWriterOption,
+) (*GCSDatabaseWriter, error) {
+	dbWriter := &GCSDatabaseWriter{
+		client:     client,
+		bucket:     bucket,
+		encryption: encryption,
+		verbose:    verbose,
+	}
+
+	for _, opt := range opts {
+		opt(dbWriter)
+	}
+
+	return dbWriter, nil
+}
+
+// NewGCSDatabaseWriterFromConfig builds the underlying *storage.Client from gcsConfig and returns a new
+// GCSDatabaseWriter.
+func NewGCSDatabaseWriterFromConfig(
+	ctx context.Context,
+	gcsConfig GCSConfig,
+	bucket string,
+	verbose bool,
+	encryption GCSEncryptionConfig,
+	opts ...GCSWriterOption,
+) (*GCSDatabaseWriter, error) {
+	var clientOpts []option.ClientOption
+	if gcsConfig.CredentialsFile != "" {
+		clientOpts = append(clientOpts, option.WithCredentialsFile(gcsConfig.CredentialsFile))
+	}
+
+	client, err := storage.NewClient(ctx, clientOpts...)
+	if err != nil {
+		return nil, fmt.Errorf("building GCS client: %w", err)
+	}
+
+	return NewGCSDatabaseWriter(client, bucket, verbose, encryption, opts...)
+}
+
+func (writer *GCSDatabaseWriter) getObjectKey(editionID string) string {
+	if writer.prefix == "" {
+		return editionID + extension
+	}
+
+	return writer.prefix + "/" + editionID + extension
+}
+
+// GetHash returns the MD5 of editionID's current object, read from its GCS ObjectAttrs, or ZeroMD5 if the
+// object doesn't exist yet.
+func (writer *GCSDatabaseWriter) GetHash(editionID string) (string, error) {
+	ctx := context.TODO()
+	objectKey := writer.getObjectKey(editionID)
+
+	attrs, err := writer.client.Bucket(writer.bucket).Object(objectKey).Attrs(ctx)
+	if err != nil {
+		if errors.Is(err, storage.ErrObjectNotExist) {
+			return ZeroMD5, nil
+		}
+
+		return "", fmt.Errorf("getting attributes for %s in bucket %s: %w", objectKey, writer.bucket, err)
+	}
+
+	return hex.EncodeToString(attrs.MD5), nil
+}
+
+// GetLastModified reads back the MaxMind source modification date recorded in the DateModifiedTag custom
+// metadata key when the edition was last written, rather than relying on GCS's Updated time, which changes
+// on metageneration updates unrelated to content.
+func (writer *GCSDatabaseWriter) GetLastModified(editionID string) (time.Time, error) {
+	ctx := context.TODO()
+	objectKey := writer.getObjectKey(editionID)
+
+	attrs, err := writer.client.Bucket(writer.bucket).Object(objectKey).Attrs(ctx)
+	if err != nil {
+		if errors.Is(err, storage.ErrObjectNotExist) {
+			return time.Time{}, nil
+		}
+
+		return time.Time{}, fmt.Errorf("getting attributes for %s in bucket %s: %w", objectKey, writer.bucket, err)
+	}
+
+	raw, ok := attrs.Metadata[DateModifiedTag]
+	if !ok {
+		return time.Time{}, nil
+	}
+
+	lastModified, err := time.Parse(time.RFC3339, raw)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("parsing %s metadata for %s: %w", DateModifiedTag, objectKey, err)
+	}
+
+	return lastModified, nil
+}
+
+// Write streams reader directly into a GCS object, validating its MD5 on the fly via the same
+// hashValidatingReader the S3 backend uses.
+func (writer *GCSDatabaseWriter) Write(
+	editionID string,
+	reader io.ReadCloser,
+	newMD5 string,
+	lastModified time.Time,
+) (err error) {
+	defer func() {
+		_, _ = io.Copy(io.Discard, reader) //nolint:errcheck // Best effort.
+		if closeErr := reader.Close(); closeErr != nil {
+			err = errors.Join(err, fmt.Errorf("closing reader for %s: %w", editionID, closeErr))
+		}
+	}()
+
+	objectKey := writer.getObjectKey(editionID)
+
+	// Use a cancelable context so a hash mismatch can abort the write instead of finalizing it: by the
+	// time newHashValidatingReader detects the mismatch (at EOF), io.Copy has already pushed every real
+	// byte through writerHandle, so calling Close here would commit the corrupt object rather than
+	// discard it.
+	ctx, cancel := context.WithCancel(context.TODO())
+	defer cancel()
+
+	object := writer.client.Bucket(writer.bucket).Object(objectKey)
+
+	writerHandle := object.NewWriter(ctx)
+	writerHandle.Metadata = map[string]string{
+		DateModifiedTag: lastModified.UTC().Format(time.RFC3339),
+	}
+	if writer.encryption.KMSKeyName != "" {
+		writerHandle.KMSKeyName = writer.encryption.KMSKeyName
+	}
+
+	if _, copyErr := io.Copy(writerHandle, newHashValidatingReader(reader, newMD5)); copyErr != nil {
+		cancel()
+
+		if errors.Is(copyErr, errHashMismatch) {
+			return fmt.Errorf("validating hash for %s: %w", editionID, copyErr)
+		}
+
+		return fmt.Errorf("writing %s to GCS: %w", editionID, copyErr)
+	}
+
+	if err := writerHandle.Close(); err != nil {
+		return fmt.Errorf("finalizing write of %s to GCS: %w", editionID, err)
+	}
+
+	if writer.verbose {
+		log.Printf("Database %s successfully updated: %+v", editionID, newMD5)
+	}
+	return nil
+}
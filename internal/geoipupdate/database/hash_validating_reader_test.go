@@ -0,0 +1,51 @@
+package database
+
+import (
+	"crypto/md5" //nolint:gosec // see s3_file_writer.go import comment.
+	"encoding/hex"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+)
+
+func md5Hex(s string) string {
+	sum := md5.Sum([]byte(s)) //nolint:gosec // see s3_file_writer.go import comment.
+	return hex.EncodeToString(sum[:])
+}
+
+func TestHashValidatingReader_PassesThroughOnMatch(t *testing.T) {
+	const content = "the quick brown fox jumps over the lazy dog"
+
+	r := newHashValidatingReader(strings.NewReader(content), md5Hex(content))
+
+	got, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if string(got) != content {
+		t.Fatalf("read %q, want %q", got, content)
+	}
+}
+
+func TestHashValidatingReader_ErrorsOnMismatchAtEOF(t *testing.T) {
+	const content = "the quick brown fox jumps over the lazy dog"
+
+	r := newHashValidatingReader(strings.NewReader(content), md5Hex("a different string"))
+
+	_, err := io.ReadAll(r)
+	if !errors.Is(err, errHashMismatch) {
+		t.Fatalf("ReadAll error = %v, want errHashMismatch", err)
+	}
+}
+
+func TestHashValidatingReader_DoesNotErrorBeforeEOF(t *testing.T) {
+	const content = "the quick brown fox jumps over the lazy dog"
+
+	r := newHashValidatingReader(strings.NewReader(content), md5Hex("a different string"))
+
+	buf := make([]byte, 4)
+	if _, err := r.Read(buf); err != nil {
+		t.Fatalf("unexpected error before EOF: %v", err)
+	}
+}
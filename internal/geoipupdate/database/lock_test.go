@@ -0,0 +1,253 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+)
+
+// fakeS3Lock is the subset of S3 object state a lock object needs: whether it exists, its "expires-at"
+// metadata, and an ETag that changes every time the object is (re)created, so conditional deletes can be
+// exercised the same way S3's own IfMatch would reject a stale ETag.
+type fakeS3Lock struct {
+	mu         sync.Mutex
+	exists     bool
+	expiresAt  string
+	etag       string
+	generation int
+}
+
+// newFakeS3LockServer returns an httptest.Server that implements just enough of the S3 HTTP API
+// (conditional PutObject via If-None-Match, HeadObject, conditional DeleteObject via If-Match) to drive
+// acquireLock/releaseLock's state machine without a live S3 backend.
+func newFakeS3LockServer(t *testing.T) (*httptest.Server, *fakeS3Lock) {
+	t.Helper()
+
+	lock := &fakeS3Lock{}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		lock.mu.Lock()
+		defer lock.mu.Unlock()
+
+		switch r.Method {
+		case http.MethodPut:
+			if r.Header.Get("If-None-Match") == "*" && lock.exists {
+				w.WriteHeader(http.StatusPreconditionFailed)
+
+				return
+			}
+
+			lock.exists = true
+			lock.expiresAt = r.Header.Get("X-Amz-Meta-Expires-At")
+			lock.generation++
+			lock.etag = fmt.Sprintf(`"lock-etag-%d"`, lock.generation)
+			w.Header().Set("ETag", lock.etag)
+			w.WriteHeader(http.StatusOK)
+		case http.MethodHead:
+			if !lock.exists {
+				w.WriteHeader(http.StatusNotFound)
+
+				return
+			}
+
+			w.Header().Set("X-Amz-Meta-Expires-At", lock.expiresAt)
+			w.Header().Set("ETag", lock.etag)
+			w.WriteHeader(http.StatusOK)
+		case http.MethodDelete:
+			if ifMatch := r.Header.Get("If-Match"); ifMatch != "" && lock.exists && ifMatch != lock.etag {
+				w.WriteHeader(http.StatusPreconditionFailed)
+
+				return
+			}
+
+			lock.exists = false
+			lock.expiresAt = ""
+			lock.etag = ""
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	return server, lock
+}
+
+func newTestS3DatabaseWriter(t *testing.T, endpoint string, lockTTL time.Duration) *S3DatabaseWriter {
+	t.Helper()
+
+	client := s3.New(s3.Options{
+		Region:       "us-east-1",
+		BaseEndpoint: aws.String(endpoint),
+		UsePathStyle: true,
+		Credentials:  credentials.NewStaticCredentialsProvider("test", "test", ""),
+	})
+
+	writer, err := NewS3DatabaseWriter(client, "test-bucket", false, EncryptionConfig{Mode: EncryptionAES256},
+		WithLockTTL(lockTTL))
+	if err != nil {
+		t.Fatalf("NewS3DatabaseWriter: %v", err)
+	}
+
+	return writer
+}
+
+func TestAcquireLock_SucceedsWhenNoLockExists(t *testing.T) {
+	server, lock := newFakeS3LockServer(t)
+	writer := newTestS3DatabaseWriter(t, server.URL, defaultLockTTL)
+
+	etag, err := writer.acquireLock(context.Background(), "GeoLite2-City.mmdb")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !lock.exists {
+		t.Fatal("expected a lock object to have been created")
+	}
+	if etag == "" {
+		t.Fatal("expected acquireLock to return the created lock's ETag")
+	}
+}
+
+func TestAcquireLock_FailsWhenLiveLockIsHeld(t *testing.T) {
+	server, lock := newFakeS3LockServer(t)
+	writer := newTestS3DatabaseWriter(t, server.URL, defaultLockTTL)
+
+	lock.exists = true
+	lock.expiresAt = time.Now().Add(time.Hour).Format(time.RFC3339)
+
+	_, err := writer.acquireLock(context.Background(), "GeoLite2-City.mmdb")
+	if !errors.Is(err, errConcurrentUpdate) {
+		t.Fatalf("acquireLock error = %v, want errConcurrentUpdate", err)
+	}
+}
+
+func TestAcquireLock_ClearsAnExpiredLockAndRetries(t *testing.T) {
+	server, lock := newFakeS3LockServer(t)
+	writer := newTestS3DatabaseWriter(t, server.URL, defaultLockTTL)
+
+	lock.exists = true
+	lock.expiresAt = time.Now().Add(-time.Hour).Format(time.RFC3339)
+
+	previousEtag := lock.etag
+
+	etag, err := writer.acquireLock(context.Background(), "GeoLite2-City.mmdb")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !lock.exists {
+		t.Fatal("expected the expired lock to have been replaced with a fresh one")
+	}
+	if etag == previousEtag {
+		t.Fatal("expected the refreshed lock to carry a new ETag")
+	}
+
+	expiresAt, err := time.Parse(time.RFC3339, lock.expiresAt)
+	if err != nil {
+		t.Fatalf("parsing refreshed expires-at: %v", err)
+	}
+	if !expiresAt.After(time.Now()) {
+		t.Fatalf("refreshed lock expires-at %v is not in the future", expiresAt)
+	}
+}
+
+func TestLockExpired_TreatsAMissingLockAsExpired(t *testing.T) {
+	server, _ := newFakeS3LockServer(t)
+	writer := newTestS3DatabaseWriter(t, server.URL, defaultLockTTL)
+
+	expired, err := writer.lockExpired(context.Background(), lockKey("GeoLite2-City.mmdb"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !expired {
+		t.Fatal("expected a missing lock to be treated as expired")
+	}
+}
+
+func TestLockExpired_HonorsRecordedExpiry(t *testing.T) {
+	server, lock := newFakeS3LockServer(t)
+	writer := newTestS3DatabaseWriter(t, server.URL, defaultLockTTL)
+
+	key := lockKey("GeoLite2-City.mmdb")
+
+	lock.exists = true
+	lock.expiresAt = time.Now().Add(time.Hour).Format(time.RFC3339)
+
+	expired, err := writer.lockExpired(context.Background(), key)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if expired {
+		t.Fatal("expected a not-yet-expired lock to report expired=false")
+	}
+
+	lock.expiresAt = time.Now().Add(-time.Hour).Format(time.RFC3339)
+
+	expired, err = writer.lockExpired(context.Background(), key)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !expired {
+		t.Fatal("expected a past-expiry lock to report expired=true")
+	}
+}
+
+func TestReleaseLock_DeletesTheOwnedLock(t *testing.T) {
+	server, lock := newFakeS3LockServer(t)
+	writer := newTestS3DatabaseWriter(t, server.URL, defaultLockTTL)
+
+	key := "GeoLite2-City.mmdb"
+
+	etag, err := writer.acquireLock(context.Background(), key)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if err := writer.releaseLock(context.Background(), key, etag); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if lock.exists {
+		t.Fatal("expected the lock object to have been deleted")
+	}
+}
+
+// TestReleaseLock_DoesNotDeleteALockReclaimedByAnotherWriter covers the race where this writer's upload ran
+// longer than lockTTL: another writer saw the lock as expired, deleted it, and created its own live lock
+// before this writer got around to releasing what it thinks is still its lock. Presenting the stale ETag
+// must fail rather than delete the second writer's lock out from under it.
+func TestReleaseLock_DoesNotDeleteALockReclaimedByAnotherWriter(t *testing.T) {
+	server, lock := newFakeS3LockServer(t)
+	writer := newTestS3DatabaseWriter(t, server.URL, defaultLockTTL)
+
+	key := "GeoLite2-City.mmdb"
+
+	staleEtag, err := writer.acquireLock(context.Background(), key)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Simulate another writer reclaiming the lock after it expired out from under the first writer.
+	lock.expiresAt = time.Now().Add(-time.Hour).Format(time.RFC3339)
+	if _, err := writer.acquireLock(context.Background(), key); err != nil {
+		t.Fatalf("unexpected error reclaiming the expired lock: %v", err)
+	}
+
+	if err := writer.releaseLock(context.Background(), key, staleEtag); !errors.Is(err, errConcurrentUpdate) {
+		t.Fatalf("releaseLock with a stale ETag error = %v, want errConcurrentUpdate", err)
+	}
+
+	if !lock.exists {
+		t.Fatal("expected the reclaiming writer's lock to survive the stale release")
+	}
+}
@@ -2,75 +2,521 @@ package database
 
 import (
 	"context"
+	"crypto/md5" //nolint:gosec // MD5 is the hash MaxMind and S3 ETags use, not used for security here.
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
 	"errors"
 	"fmt"
 	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/aws/aws-sdk-go-v2/feature/s3/manager"
 	"github.com/aws/aws-sdk-go-v2/service/s3"
 	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	smithyhttp "github.com/aws/smithy-go/transport/http"
 	"io"
 	"log"
+	"net/http"
+	"net/url"
 	"os"
-	"path"
+	"strings"
 	"time"
 )
 
+// defaultPartSize and defaultConcurrency are the manager.Uploader defaults used when S3DatabaseWriter is
+// constructed without overriding them via WithPartSize/WithConcurrency.
+const (
+	defaultPartSize    = manager.DefaultUploadPartSize
+	defaultConcurrency = manager.DefaultUploadConcurrency
+)
+
 // DateModifiedTag is the name of the tag on an S3 bucket for storing the modified date information received from
 //
 //	the MaxMind servers
 const DateModifiedTag = "DateOfSourceDatabaseModification"
 
+// contentMD5MetadataKey is the object metadata key Write stores the real content MD5 under. It's needed
+// because S3's own ETag is only the content MD5 for objects uploaded in a single part; for a multipart
+// upload (the default for any edition over partSize, which includes GeoIP2-City/ISP/Anonymous-IP) the ETag
+// is "md5-of-part-md5s-N" instead, so GetHash can't compare it against the MD5 MaxMind reports.
+const contentMD5MetadataKey = "content-md5"
+
+// S3Config carries the connection details needed to build an *s3.Client that targets either AWS S3 or an
+// S3-compatible backend such as MinIO, Ceph RadosGW, DigitalOcean Spaces, Wasabi, or LocalStack.
+//
+// When AccessKeyID is empty, the client falls back to the default AWS credential chain (environment variables,
+// shared config/credentials files, EC2/ECS instance role, or IRSA/web-identity), matching the common "env_auth"
+// behavior offered by other S3 clients.
+type S3Config struct {
+	// Endpoint overrides the AWS-derived endpoint with a host and optional port, without a scheme, e.g.
+	// "minio.example.com:9000"; the scheme is https, or http when DisableSSL is set. Leave empty to use
+	// the default AWS endpoint for Region.
+	Endpoint string
+	// Region is the AWS region to sign requests for. Required even for non-AWS backends that ignore it.
+	Region string
+	// AccessKeyID and SecretAccessKey are static credentials. Leave both empty to use the default AWS
+	// credential chain instead.
+	AccessKeyID     string
+	SecretAccessKey string
+	// SessionToken is an optional session token to pair with temporary static credentials.
+	SessionToken string
+	// UsePathStyle forces path-style addressing (https://host/bucket/key instead of
+	// https://bucket.host/key), which most S3-compatible backends require.
+	UsePathStyle bool
+	// DisableSSL connects to Endpoint over plain HTTP instead of HTTPS.
+	DisableSSL bool
+	// CACertPath, if set, is a PEM file used to validate Endpoint's certificate, for self-signed clusters.
+	CACertPath string
+}
+
+// newS3Client builds an *s3.Client from an S3Config, wiring up custom endpoints, credentials, path-style
+// addressing, and TLS trust as configured.
+func newS3Client(ctx context.Context, s3Config S3Config) (*s3.Client, error) {
+	var optFns []func(*config.LoadOptions) error
+
+	if s3Config.Region != "" {
+		optFns = append(optFns, config.WithRegion(s3Config.Region))
+	}
+
+	if s3Config.AccessKeyID != "" {
+		optFns = append(optFns, config.WithCredentialsProvider(
+			credentials.NewStaticCredentialsProvider(
+				s3Config.AccessKeyID,
+				s3Config.SecretAccessKey,
+				s3Config.SessionToken,
+			),
+		))
+	}
+
+	if s3Config.CACertPath != "" {
+		httpClient, err := httpClientWithCA(s3Config.CACertPath)
+		if err != nil {
+			return nil, fmt.Errorf("configuring CA certificate for S3 endpoint: %w", err)
+		}
+		optFns = append(optFns, config.WithHTTPClient(httpClient))
+	}
+
+	awsConfig, err := config.LoadDefaultConfig(ctx, optFns...)
+	if err != nil {
+		return nil, fmt.Errorf("loading AWS configuration: %w", err)
+	}
+
+	return s3.NewFromConfig(awsConfig, func(o *s3.Options) {
+		if s3Config.Endpoint != "" {
+			scheme := "https"
+			if s3Config.DisableSSL {
+				scheme = "http"
+			}
+			o.BaseEndpoint = aws.String(scheme + "://" + s3Config.Endpoint)
+		}
+		o.UsePathStyle = s3Config.UsePathStyle
+	}), nil
+}
+
+// httpClientWithCA returns an *http.Client that trusts the PEM-encoded certificate at caCertPath in addition
+// to the system trust store.
+func httpClientWithCA(caCertPath string) (*http.Client, error) {
+	caCert, err := os.ReadFile(caCertPath)
+	if err != nil {
+		return nil, fmt.Errorf("reading CA certificate %s: %w", caCertPath, err)
+	}
+
+	pool, err := x509.SystemCertPool()
+	if err != nil || pool == nil {
+		pool = x509.NewCertPool()
+	}
+	if !pool.AppendCertsFromPEM(caCert) {
+		return nil, fmt.Errorf("no valid certificates found in %s", caCertPath)
+	}
+
+	return &http.Client{
+		Transport: &http.Transport{
+			TLSClientConfig: &tls.Config{
+				RootCAs:    pool,
+				MinVersion: tls.VersionTLS12,
+			},
+		},
+	}, nil
+}
+
+// EncryptionMode selects how S3DatabaseWriter protects objects at rest.
+type EncryptionMode string
+
+const (
+	// EncryptionNone disables server-side encryption.
+	EncryptionNone EncryptionMode = "none"
+	// EncryptionAES256 is SSE-S3, S3-managed AES-256 keys. This is the default when EncryptionConfig is
+	// left zero-valued.
+	EncryptionAES256 EncryptionMode = "AES256"
+	// EncryptionKMS is SSE-KMS, using a customer managed or AWS managed KMS key.
+	EncryptionKMS EncryptionMode = "aws:kms"
+	// EncryptionSSEC is SSE-C, using a caller-supplied 256-bit key that S3 never stores.
+	EncryptionSSEC EncryptionMode = "SSE-C"
+)
+
+// EncryptionConfig selects the server-side encryption S3DatabaseWriter applies to objects it writes, and
+// (for SSE-C) the headers needed to read them back.
+type EncryptionConfig struct {
+	Mode EncryptionMode
+	// KMSKeyID is the KMS key to encrypt with when Mode is EncryptionKMS. Leave empty to use the bucket's
+	// default AWS-managed key.
+	KMSKeyID string
+	// BucketKeyEnabled enables an S3 bucket key to reduce KMS request costs. Only applies to EncryptionKMS.
+	BucketKeyEnabled bool
+	// SSECustomerKey is the 256-bit (32 byte) key used when Mode is EncryptionSSEC.
+	SSECustomerKey []byte
+}
+
+// applyToPut sets the encryption-related fields on a PutObjectInput for this configuration.
+func (enc EncryptionConfig) applyToPut(input *s3.PutObjectInput) error {
+	switch enc.Mode {
+	case "", EncryptionNone:
+	case EncryptionAES256:
+		input.ServerSideEncryption = types.ServerSideEncryptionAes256
+	case EncryptionKMS:
+		input.ServerSideEncryption = types.ServerSideEncryptionAwsKms
+		if enc.KMSKeyID != "" {
+			input.SSEKMSKeyId = aws.String(enc.KMSKeyID)
+		}
+		input.BucketKeyEnabled = aws.Bool(enc.BucketKeyEnabled)
+	case EncryptionSSEC:
+		algorithm, key, keyMD5, err := sseCustomerHeaders(enc.SSECustomerKey)
+		if err != nil {
+			return err
+		}
+		input.SSECustomerAlgorithm = aws.String(algorithm)
+		input.SSECustomerKey = aws.String(key)
+		input.SSECustomerKeyMD5 = aws.String(keyMD5)
+	default:
+		return fmt.Errorf("unknown encryption mode %q", enc.Mode)
+	}
+
+	return nil
+}
+
+// applyToGet sets the SSE-C headers needed to read back an object encrypted under this configuration.
+// Other modes require no extra headers on GetObject/GetObjectTagging.
+func (enc EncryptionConfig) applyToGet(input *s3.GetObjectInput) error {
+	if enc.Mode != EncryptionSSEC {
+		return nil
+	}
+
+	algorithm, key, keyMD5, err := sseCustomerHeaders(enc.SSECustomerKey)
+	if err != nil {
+		return err
+	}
+	input.SSECustomerAlgorithm = aws.String(algorithm)
+	input.SSECustomerKey = aws.String(key)
+	input.SSECustomerKeyMD5 = aws.String(keyMD5)
+
+	return nil
+}
+
+// applyToHead sets the SSE-C headers needed to read back an object's metadata under this configuration.
+// Other modes require no extra headers on HeadObject.
+func (enc EncryptionConfig) applyToHead(input *s3.HeadObjectInput) error {
+	if enc.Mode != EncryptionSSEC {
+		return nil
+	}
+
+	algorithm, key, keyMD5, err := sseCustomerHeaders(enc.SSECustomerKey)
+	if err != nil {
+		return err
+	}
+	input.SSECustomerAlgorithm = aws.String(algorithm)
+	input.SSECustomerKey = aws.String(key)
+	input.SSECustomerKeyMD5 = aws.String(keyMD5)
+
+	return nil
+}
+
+// sseCustomerHeaders derives the SSE-C algorithm, base64-encoded key, and base64-encoded key MD5 that S3
+// requires on every request against an SSE-C object.
+func sseCustomerHeaders(key []byte) (algorithm, base64Key, base64KeyMD5 string, err error) {
+	const sseCustomerKeyLength = 32 // 256 bits.
+	if len(key) != sseCustomerKeyLength {
+		return "", "", "", fmt.Errorf("SSE-C key must be %d bytes, got %d", sseCustomerKeyLength, len(key))
+	}
+
+	keyMD5 := md5.Sum(key) //nolint:gosec // see import comment.
+
+	return "AES256", base64.StdEncoding.EncodeToString(key), base64.StdEncoding.EncodeToString(keyMD5[:]), nil
+}
+
+// ConcurrencyControlMode selects how S3DatabaseWriter protects against two geoipupdate processes (e.g. cron
+// on several hosts, or an HA scheduler) racing to write the same edition.
+type ConcurrencyControlMode string
+
+const (
+	// ConcurrencyControlNone performs an unconditional PutObject, same as if no control were configured.
+	ConcurrencyControlNone ConcurrencyControlMode = "none"
+	// ConcurrencyControlIfMatch conditions the write on the object's current ETag (or its absence),
+	// failing the write instead of silently clobbering a concurrent update.
+	ConcurrencyControlIfMatch ConcurrencyControlMode = "if-match"
+	// ConcurrencyControlAdvisoryLock acquires a sibling ".lock" object before writing and releases it
+	// afterward, so a racing writer backs off instead of both processes uploading at once.
+	ConcurrencyControlAdvisoryLock ConcurrencyControlMode = "advisory-lock"
+)
+
+// errConcurrentUpdate is returned by Write when another process updated (or is updating) the edition first.
+// Because Write consumes reader exactly once, recovering requires the caller to re-download the edition and
+// call Write again; this mirrors how the rest of the update pipeline already retries a failed edition.
+var errConcurrentUpdate = errors.New("edition was updated concurrently by another writer")
+
 // S3DatabaseWriter is a databaseWriter that stores the database to a target s3 bucket and key
 type S3DatabaseWriter struct {
-	s3Client          *s3.Client
-	s3Bucket          string
-	disableEncryption bool
-	verbose           bool
+	s3Client           *s3.Client
+	s3Bucket           string
+	encryption         EncryptionConfig
+	verbose            bool
+	partSize           int64
+	concurrency        int
+	concurrencyControl ConcurrencyControlMode
+	lockTTL            time.Duration
+	keyPrefix          string
 }
 
-// NewS3DatabaseWriter creates a new S3DatabaseWriter, creating necessary locks and temporary files to protect from
-//
-//	concurrent writes
-func NewS3DatabaseWriter(s3Client *s3.Client, s3Bucket string, verbose bool) (*S3DatabaseWriter, error) {
+// defaultLockTTL bounds how long an advisory lock is honored before a competing writer is allowed to treat
+// it as abandoned (e.g. because its owner crashed without releasing it).
+const defaultLockTTL = 10 * time.Minute
+
+// S3WriterOption configures optional behavior on an S3DatabaseWriter that most callers don't need to set
+// explicitly.
+type S3WriterOption func(*S3DatabaseWriter)
+
+// WithPartSize overrides the multipart upload part size in bytes. It must be at least 5 MiB, per the S3
+// multipart upload API.
+func WithPartSize(partSize int64) S3WriterOption {
+	return func(writer *S3DatabaseWriter) {
+		writer.partSize = partSize
+	}
+}
+
+// WithConcurrency overrides how many parts of a multipart upload are sent concurrently.
+func WithConcurrency(concurrency int) S3WriterOption {
+	return func(writer *S3DatabaseWriter) {
+		writer.concurrency = concurrency
+	}
+}
+
+// WithConcurrencyControl selects how Write guards against racing updaters targeting the same bucket and
+// key. Defaults to ConcurrencyControlNone.
+func WithConcurrencyControl(mode ConcurrencyControlMode) S3WriterOption {
+	return func(writer *S3DatabaseWriter) {
+		writer.concurrencyControl = mode
+	}
+}
+
+// WithLockTTL overrides how long an advisory lock is honored before it is considered abandoned. Only takes
+// effect with ConcurrencyControlAdvisoryLock.
+func WithLockTTL(ttl time.Duration) S3WriterOption {
+	return func(writer *S3DatabaseWriter) {
+		writer.lockTTL = ttl
+	}
+}
+
+// WithPrefix stores editions under keyPrefix within the bucket, e.g. so a single bucket can host multiple
+// geoipupdate deployments side by side. Matches the "prefix" segment of an "s3://bucket/prefix"
+// destination URL.
+func WithPrefix(keyPrefix string) S3WriterOption {
+	return func(writer *S3DatabaseWriter) {
+		writer.keyPrefix = keyPrefix
+	}
+}
+
+// NewS3DatabaseWriter assembles an S3DatabaseWriter from an already-configured *s3.Client, applying opts
+// over the defaults (no encryption beyond EncryptionAES256, ConcurrencyControlNone, defaultLockTTL).
+func NewS3DatabaseWriter(
+	s3Client *s3.Client,
+	s3Bucket string,
+	verbose bool,
+	encryption EncryptionConfig,
+	opts ...S3WriterOption,
+) (*S3DatabaseWriter, error) {
+	if encryption.Mode == "" {
+		encryption.Mode = EncryptionAES256
+	}
+
 	dbWriter := &S3DatabaseWriter{
-		s3Client:          s3Client,
-		s3Bucket:          s3Bucket,
-		disableEncryption: false,
-		verbose:           verbose,
+		s3Client:           s3Client,
+		s3Bucket:           s3Bucket,
+		encryption:         encryption,
+		verbose:            verbose,
+		partSize:           defaultPartSize,
+		concurrency:        defaultConcurrency,
+		concurrencyControl: ConcurrencyControlNone,
+		lockTTL:            defaultLockTTL,
+	}
+
+	for _, opt := range opts {
+		opt(dbWriter)
 	}
 
 	return dbWriter, nil
 }
 
-// GetHash uses the s3 bucket and key to query for the ETag (the MD5) for the S3 object
-func (writer *S3DatabaseWriter) GetHash(editionID string) (string, error) {
+// NewS3DatabaseWriterFromConfig builds the underlying *s3.Client from s3Config and returns a new
+// S3DatabaseWriter, so callers configuring via CLI flags or a config file don't need to construct the AWS
+// SDK client themselves.
+func NewS3DatabaseWriterFromConfig(
+	ctx context.Context,
+	s3Config S3Config,
+	s3Bucket string,
+	verbose bool,
+	encryption EncryptionConfig,
+	opts ...S3WriterOption,
+) (*S3DatabaseWriter, error) {
+	s3Client, err := newS3Client(ctx, s3Config)
+	if err != nil {
+		return nil, fmt.Errorf("building S3 client: %w", err)
+	}
+
+	return NewS3DatabaseWriter(s3Client, s3Bucket, verbose, encryption, opts...)
+}
+
+// GetLastModified reads back the MaxMind source modification date that was recorded in the DateModifiedTag
+// object tag when the edition was last written, rather than relying on S3's own LastModified, which resets
+// whenever the object is re-copied or re-encrypted.
+func (writer *S3DatabaseWriter) GetLastModified(editionID string) (time.Time, error) {
 	objectKey := writer.getObjectKey(editionID)
 
-	response, err := writer.s3Client.GetObject(context.TODO(), &s3.GetObjectInput{
+	response, err := writer.s3Client.GetObjectTagging(context.TODO(), &s3.GetObjectTaggingInput{
 		Bucket: aws.String(writer.s3Bucket),
 		Key:    aws.String(objectKey),
 	})
-
 	if err != nil {
 		var noSuchKeyErr *types.NoSuchKey
 		if errors.As(err, &noSuchKeyErr) {
+			return time.Time{}, nil
+		}
+
+		return time.Time{}, fmt.Errorf("getting tags for %s in bucket %s: %w", objectKey, writer.s3Bucket, err)
+	}
+
+	for _, tag := range response.TagSet {
+		if tag.Key == nil || *tag.Key != DateModifiedTag {
+			continue
+		}
+
+		lastModified, err := time.Parse(time.RFC3339, aws.ToString(tag.Value))
+		if err != nil {
+			return time.Time{}, fmt.Errorf("parsing %s tag for %s: %w", DateModifiedTag, objectKey, err)
+		}
+
+		return lastModified, nil
+	}
+
+	return time.Time{}, nil
+}
+
+// GetHash returns the content MD5 of editionID's current S3 object, or ZeroMD5 if it doesn't exist yet.
+//
+// It reads the MD5 back from the contentMD5MetadataKey object metadata that Write stores rather than
+// trusting the object's ETag: for a multipart upload (the default for any edition over partSize) S3's ETag
+// is "md5-of-part-md5s-N", not the content MD5, which would otherwise make every update run believe the
+// database had changed. Objects written before this metadata was tracked fall back to the ETag, which is
+// only valid when it doesn't carry the multipart "-N" suffix.
+func (writer *S3DatabaseWriter) GetHash(editionID string) (string, error) {
+	objectKey := writer.getObjectKey(editionID)
+
+	headObject := &s3.HeadObjectInput{
+		Bucket: aws.String(writer.s3Bucket),
+		Key:    aws.String(objectKey),
+	}
+	if err := writer.encryption.applyToHead(headObject); err != nil {
+		return "", fmt.Errorf("applying encryption settings for %s: %w", objectKey, err)
+	}
+
+	response, err := writer.s3Client.HeadObject(context.TODO(), headObject)
+	if err != nil {
+		var notFound *types.NotFound
+		if errors.As(err, &notFound) {
 			return ZeroMD5, nil
 		}
 
-		return "", fmt.Errorf("failed to Get Object %s in bucket %s: %w", objectKey, writer.s3Bucket, err)
+		return "", fmt.Errorf("failed to Head Object %s in bucket %s: %w", objectKey, writer.s3Bucket, err)
+	}
+
+	if contentMD5, ok := response.Metadata[contentMD5MetadataKey]; ok {
+		return contentMD5, nil
 	}
 
-	return *response.ETag, nil
+	etag := strings.Trim(aws.ToString(response.ETag), `"`)
+	if !strings.Contains(etag, "-") {
+		return etag, nil
+	}
+
+	return "", fmt.Errorf(
+		"cannot determine content MD5 for %s: multipart ETag %s predates %s metadata tracking",
+		objectKey, etag, contentMD5MetadataKey,
+	)
 }
 
 func (writer *S3DatabaseWriter) getObjectKey(editionID string) string {
-	return editionID + extension
+	if writer.keyPrefix == "" {
+		return editionID + extension
+	}
+
+	return strings.TrimSuffix(writer.keyPrefix, "/") + "/" + editionID + extension
+}
+
+// dateModifiedTagging builds the URL-encoded S3 object tagging query string recording the MaxMind source
+// database's modification date, per the S3 tagging spec (e.g. "Key=Value&Key2=Value2").
+func dateModifiedTagging(lastModified time.Time) string {
+	tags := url.Values{}
+	tags.Set(DateModifiedTag, lastModified.UTC().Format(time.RFC3339))
+
+	return tags.Encode()
+}
+
+// hashValidatingReader wraps a reader, hashing every byte read as it streams through, and surfaces a hash
+// mismatch as a read error once the underlying reader is exhausted. This lets a multipart upload that is
+// already in progress learn about a bad hash and abort instead of completing a corrupt object.
+type hashValidatingReader struct {
+	reader   io.Reader
+	hash     hashWriter
+	wantHash string
+}
+
+type hashWriter interface {
+	io.Writer
+	Sum([]byte) []byte
 }
 
+func newHashValidatingReader(reader io.Reader, wantHash string) *hashValidatingReader {
+	h := md5.New() //nolint:gosec // see import comment.
+
+	return &hashValidatingReader{
+		reader:   io.TeeReader(reader, h),
+		hash:     h,
+		wantHash: wantHash,
+	}
+}
+
+func (hvr *hashValidatingReader) Read(p []byte) (int, error) {
+	n, err := hvr.reader.Read(p)
+	if errors.Is(err, io.EOF) {
+		if gotHash := hex.EncodeToString(hvr.hash.Sum(nil)); gotHash != hvr.wantHash {
+			return n, fmt.Errorf("%w: expected %s, got %s", errHashMismatch, hvr.wantHash, gotHash)
+		}
+	}
+
+	return n, err
+}
+
+// errHashMismatch is returned by hashValidatingReader when the streamed content doesn't hash to the
+// expected MD5. Write wraps it with %w so callers can detect the condition with errors.Is.
+var errHashMismatch = errors.New("hash of streamed content did not match expected hash")
+
+// Write streams reader directly into an S3 multipart upload, validating its MD5 on the fly rather than
+// staging it to a temp file first. If the streamed content doesn't hash to newMD5, the upload is aborted.
 func (writer *S3DatabaseWriter) Write(
 	editionID string,
 	reader io.ReadCloser,
 	newMD5 string,
-	_ time.Time,
+	lastModified time.Time,
 ) (err error) {
 	defer func() {
 		_, _ = io.Copy(io.Discard, reader) //nolint:errcheck // Best effort.
@@ -83,53 +529,245 @@ func (writer *S3DatabaseWriter) Write(
 	}()
 
 	key := writer.getObjectKey(editionID)
+	ctx := context.TODO()
 
-	tempFile := path.Join("tmp", key) + tempExtension
-	fw, err := newFileWriter(tempFile)
-	if err != nil {
-		return fmt.Errorf("setting up database writer for %s: %w", editionID, err)
+	if writer.concurrencyControl == ConcurrencyControlAdvisoryLock {
+		lockETag, acquireErr := writer.acquireLock(ctx, key)
+		if acquireErr != nil {
+			return fmt.Errorf("acquiring lock for %s: %w", editionID, acquireErr)
+		}
+		defer func() {
+			if releaseErr := writer.releaseLock(ctx, key, lockETag); releaseErr != nil {
+				err = errors.Join(err, fmt.Errorf("releasing lock for %s: %w", editionID, releaseErr))
+			}
+		}()
 	}
-	defer func() {
-		if closeErr := fw.close(); closeErr != nil {
-			err = errors.Join(err, fmt.Errorf("closing file writer: %w", closeErr))
+
+	uploader := manager.NewUploader(writer.s3Client, func(u *manager.Uploader) {
+		u.PartSize = writer.partSize
+		u.Concurrency = writer.concurrency
+	})
+
+	s3PutObject := &s3.PutObjectInput{
+		Bucket:  aws.String(writer.s3Bucket),
+		Key:     aws.String(key),
+		Body:    newHashValidatingReader(reader, newMD5),
+		Tagging: aws.String(dateModifiedTagging(lastModified)),
+		Metadata: map[string]string{
+			contentMD5MetadataKey: newMD5,
+		},
+	}
+
+	if err := writer.encryption.applyToPut(s3PutObject); err != nil {
+		return fmt.Errorf("applying encryption settings for %s: %w", editionID, err)
+	}
+
+	if writer.concurrencyControl == ConcurrencyControlIfMatch {
+		if err := writer.applyIfMatch(ctx, key, s3PutObject); err != nil {
+			return fmt.Errorf("checking current state of %s: %w", editionID, err)
 		}
-	}()
+	}
+
+	if _, err := uploader.Upload(ctx, s3PutObject); err != nil {
+		if errors.Is(err, errHashMismatch) {
+			return fmt.Errorf("validating hash for %s: %w", editionID, err)
+		}
+
+		var apiErr *smithyhttp.ResponseError
+		if errors.As(err, &apiErr) && apiErr.HTTPStatusCode() == http.StatusPreconditionFailed {
+			return fmt.Errorf("writing %s: %w: %w", editionID, errConcurrentUpdate, err)
+		}
+
+		return fmt.Errorf("encountered an error writing file to S3: %w", err)
+	}
 
-	if err = fw.write(reader); err != nil {
-		return fmt.Errorf("writing to the temp file for %s: %w", editionID, err)
+	if writer.verbose {
+		log.Printf("Database %s successfully updated: %+v", editionID, newMD5)
 	}
+	return nil
+}
 
-	// make sure the hash of the temp file matches the expected hash.
-	if err = fw.validateHash(newMD5); err != nil {
-		return fmt.Errorf("validating hash for %s: %w", editionID, err)
+// applyIfMatch conditions s3PutObject on the object's current ETag, so a concurrent writer that already
+// updated the object causes this write to fail with PreconditionFailed instead of silently overwriting it.
+// If the object doesn't exist yet, the write is conditioned on it still not existing. Like GetHash, this
+// uses HeadObject rather than GetObject so checking the ETag doesn't download the entire existing database.
+func (writer *S3DatabaseWriter) applyIfMatch(ctx context.Context, key string, input *s3.PutObjectInput) error {
+	headObject := &s3.HeadObjectInput{
+		Bucket: aws.String(writer.s3Bucket),
+		Key:    aws.String(key),
+	}
+	if err := writer.encryption.applyToHead(headObject); err != nil {
+		return fmt.Errorf("applying encryption settings for %s: %w", key, err)
 	}
 
-	s3Body, err := os.OpenFile(tempFile, os.O_RDONLY, 0o644)
+	response, err := writer.s3Client.HeadObject(ctx, headObject)
 	if err != nil {
-		return fmt.Errorf("opening temp file to read for %s: %w", editionID, err)
+		var notFound *types.NotFound
+		if errors.As(err, &notFound) {
+			input.IfNoneMatch = aws.String("*")
+			return nil
+		}
+
+		return fmt.Errorf("checking for existing object %s: %w", key, err)
 	}
-	defer func() {
-		if closeErr := s3Body.Close(); closeErr != nil {
-			err = errors.Join(err, fmt.Errorf("closing file reader: %w", closeErr))
+
+	input.IfMatch = response.ETag
+
+	return nil
+}
+
+// lockKey returns the sibling object used as an advisory lock for the given data object key.
+func lockKey(key string) string {
+	return key + ".lock"
+}
+
+// acquireLock creates an advisory lock object for key, retrying once if a live lock is held by another
+// writer. A lock is considered live until writer.lockTTL after it was created, recorded in the lock
+// object's "expires-at" metadata; an expired lock is deleted and re-acquired. On success it returns the
+// ETag of the lock object this writer created, which releaseLock must present to prove ownership when
+// releasing it.
+func (writer *S3DatabaseWriter) acquireLock(ctx context.Context, key string) (string, error) {
+	const maxAttempts = 2
+
+	var lastErr error
+	for attempt := 0; attempt < maxAttempts; attempt++ {
+		etag, err := writer.tryAcquireLock(ctx, key)
+		if err == nil {
+			return etag, nil
 		}
-	}()
+		lastErr = err
 
-	s3PutObject := &s3.PutObjectInput{
-		Bucket:               aws.String(writer.s3Bucket),
-		Key:                  aws.String(key),
-		ServerSideEncryption: types.ServerSideEncryptionAes256,
-		Body:                 s3Body,
+		if !errors.Is(err, errConcurrentUpdate) {
+			return "", err
+		}
 	}
 
-	if writer.disableEncryption {
-		s3PutObject.ServerSideEncryption = ""
+	return "", lastErr
+}
+
+func (writer *S3DatabaseWriter) tryAcquireLock(ctx context.Context, key string) (string, error) {
+	lock := lockKey(key)
+
+	putResponse, err := writer.s3Client.PutObject(ctx, &s3.PutObjectInput{
+		Bucket:      aws.String(writer.s3Bucket),
+		Key:         aws.String(lock),
+		Body:        strings.NewReader(""),
+		IfNoneMatch: aws.String("*"),
+		Metadata: map[string]string{
+			"expires-at": time.Now().Add(writer.lockTTL).Format(time.RFC3339),
+		},
+	})
+	if err == nil {
+		return aws.ToString(putResponse.ETag), nil
 	}
-	if _, err := writer.s3Client.PutObject(context.TODO(), s3PutObject); err != nil {
-		return fmt.Errorf("encountered an error writing file to S3: %w", err)
+
+	var apiErr *smithyhttp.ResponseError
+	if !errors.As(err, &apiErr) || apiErr.HTTPStatusCode() != http.StatusPreconditionFailed {
+		return "", fmt.Errorf("creating lock object %s: %w", lock, err)
 	}
 
-	if writer.verbose {
-		log.Printf("Database %s successfully updated: %+v", editionID, newMD5)
+	expired, expireErr := writer.lockExpired(ctx, lock)
+	if expireErr != nil {
+		return "", expireErr
+	}
+	if !expired {
+		return "", fmt.Errorf("%w: lock %s is held", errConcurrentUpdate, lock)
+	}
+
+	if _, delErr := writer.s3Client.DeleteObject(ctx, &s3.DeleteObjectInput{
+		Bucket: aws.String(writer.s3Bucket),
+		Key:    aws.String(lock),
+	}); delErr != nil {
+		return "", fmt.Errorf("deleting expired lock %s: %w", lock, delErr)
+	}
+
+	return "", fmt.Errorf("%w: expired lock %s was cleared", errConcurrentUpdate, lock)
+}
+
+// lockExpired reports whether the lock object's recorded "expires-at" metadata is in the past.
+func (writer *S3DatabaseWriter) lockExpired(ctx context.Context, lock string) (bool, error) {
+	head, err := writer.s3Client.HeadObject(ctx, &s3.HeadObjectInput{
+		Bucket: aws.String(writer.s3Bucket),
+		Key:    aws.String(lock),
+	})
+	if err != nil {
+		var notFound *types.NotFound
+		if errors.As(err, &notFound) {
+			return true, nil
+		}
+
+		return false, fmt.Errorf("checking lock %s: %w", lock, err)
+	}
+
+	expiresAt, err := time.Parse(time.RFC3339, head.Metadata["expires-at"])
+	if err != nil {
+		return false, fmt.Errorf("parsing expiry of lock %s: %w", lock, err)
+	}
+
+	return time.Now().After(expiresAt), nil
+}
+
+// releaseLock deletes the advisory lock object for key, making it available to the next writer. The
+// deletion is conditioned on etag (the ETag acquireLock returned when this writer created the lock) so
+// that if the lock expired and was reclaimed by another writer while this Write was still in flight (e.g. a
+// large upload that outran lockTTL), this call fails instead of deleting the other writer's live lock.
+func (writer *S3DatabaseWriter) releaseLock(ctx context.Context, key string, etag string) error {
+	lock := lockKey(key)
+
+	if _, err := writer.s3Client.DeleteObject(ctx, &s3.DeleteObjectInput{
+		Bucket:  aws.String(writer.s3Bucket),
+		Key:     aws.String(lock),
+		IfMatch: aws.String(etag),
+	}); err != nil {
+		var apiErr *smithyhttp.ResponseError
+		if errors.As(err, &apiErr) && apiErr.HTTPStatusCode() == http.StatusPreconditionFailed {
+			return fmt.Errorf("%w: lock %s was reclaimed by another writer before it could be released", errConcurrentUpdate, lock)
+		}
+
+		return fmt.Errorf("deleting lock object %s: %w", lock, err)
+	}
+
+	return nil
+}
+
+// CleanupIncompleteUploads aborts any in-progress multipart uploads in the writer's bucket that were
+// initiated more than olderThan ago, reclaiming the storage held by their uploaded parts. This is meant to
+// be run periodically (e.g. before an update run) to clean up after a process that crashed mid-upload.
+func (writer *S3DatabaseWriter) CleanupIncompleteUploads(ctx context.Context, olderThan time.Duration) error {
+	cutoff := time.Now().Add(-olderThan)
+
+	paginator := s3.NewListMultipartUploadsPaginator(writer.s3Client, &s3.ListMultipartUploadsInput{
+		Bucket: aws.String(writer.s3Bucket),
+	})
+
+	var aborted []string
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return fmt.Errorf("listing incomplete multipart uploads in bucket %s: %w", writer.s3Bucket, err)
+		}
+
+		for _, upload := range page.Uploads {
+			if upload.Initiated == nil || upload.Initiated.After(cutoff) {
+				continue
+			}
+
+			_, err := writer.s3Client.AbortMultipartUpload(ctx, &s3.AbortMultipartUploadInput{
+				Bucket:   aws.String(writer.s3Bucket),
+				Key:      upload.Key,
+				UploadId: upload.UploadId,
+			})
+			if err != nil {
+				return fmt.Errorf("aborting stale multipart upload %s for %s: %w", *upload.UploadId, *upload.Key, err)
+			}
+
+			aborted = append(aborted, *upload.Key)
+		}
 	}
+
+	if writer.verbose && len(aborted) > 0 {
+		log.Printf("Aborted %d stale multipart upload(s): %v", len(aborted), aborted)
+	}
+
 	return nil
 }
@@ -0,0 +1,143 @@
+package database
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+)
+
+func TestDateModifiedTagging(t *testing.T) {
+	lastModified := time.Date(2026, 3, 4, 5, 6, 7, 0, time.FixedZone("UTC-5", -5*60*60))
+
+	got := dateModifiedTagging(lastModified)
+
+	want := "DateOfSourceDatabaseModification=2026-03-04T10%3A06%3A07Z"
+	if got != want {
+		t.Fatalf("dateModifiedTagging(%v) = %q, want %q", lastModified, got, want)
+	}
+}
+
+func TestDateModifiedTagging_RoundTripsThroughURLValues(t *testing.T) {
+	lastModified := time.Date(2026, 7, 26, 12, 30, 0, 0, time.UTC)
+
+	tagging := dateModifiedTagging(lastModified)
+
+	values, err := url.ParseQuery(tagging)
+	if err != nil {
+		t.Fatalf("parsing tagging query %q: %v", tagging, err)
+	}
+
+	got := values.Get(DateModifiedTag)
+	want := lastModified.Format(time.RFC3339)
+	if got != want {
+		t.Fatalf("tag %s = %q, want %q", DateModifiedTag, got, want)
+	}
+}
+
+// fakeS3ObjectRequests records which HTTP methods a fakeS3ObjectServer received, so a test can confirm
+// applyIfMatch used HeadObject and never fell back to downloading the object via GetObject.
+type fakeS3ObjectRequests struct {
+	mu      sync.Mutex
+	methods []string
+}
+
+func (r *fakeS3ObjectRequests) record(method string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.methods = append(r.methods, method)
+}
+
+func (r *fakeS3ObjectRequests) saw(method string) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, m := range r.methods {
+		if m == method {
+			return true
+		}
+	}
+
+	return false
+}
+
+// newFakeS3ObjectServer returns an httptest.Server whose HEAD responses reflect exists/etag, so
+// applyIfMatch can be exercised without downloading (or even storing) an object body.
+func newFakeS3ObjectServer(t *testing.T, exists bool, etag string) (*httptest.Server, *fakeS3ObjectRequests) {
+	t.Helper()
+
+	requests := &fakeS3ObjectRequests{}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.record(r.Method)
+
+		switch r.Method {
+		case http.MethodHead:
+			if !exists {
+				w.WriteHeader(http.StatusNotFound)
+
+				return
+			}
+
+			w.Header().Set("ETag", etag)
+			w.WriteHeader(http.StatusOK)
+		default:
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	return server, requests
+}
+
+func TestApplyIfMatch_ConditionsOnExistingObjectsETagViaHeadObject(t *testing.T) {
+	const etag = `"current-etag"`
+
+	server, requests := newFakeS3ObjectServer(t, true, etag)
+	writer := newTestS3DatabaseWriter(t, server.URL, defaultLockTTL)
+
+	input := &s3.PutObjectInput{}
+	if err := writer.applyIfMatch(context.Background(), "GeoLite2-City.mmdb", input); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !requests.saw(http.MethodHead) {
+		t.Fatal("expected applyIfMatch to issue a HeadObject request")
+	}
+	if requests.saw(http.MethodGet) {
+		t.Fatal("applyIfMatch issued a GetObject request instead of relying on HeadObject alone")
+	}
+	if aws.ToString(input.IfMatch) != etag {
+		t.Errorf("IfMatch = %q, want %q", aws.ToString(input.IfMatch), etag)
+	}
+	if input.IfNoneMatch != nil {
+		t.Errorf("IfNoneMatch = %q, want unset", aws.ToString(input.IfNoneMatch))
+	}
+}
+
+func TestApplyIfMatch_ConditionsOnNonExistenceWhenObjectIsMissing(t *testing.T) {
+	server, requests := newFakeS3ObjectServer(t, false, "")
+	writer := newTestS3DatabaseWriter(t, server.URL, defaultLockTTL)
+
+	input := &s3.PutObjectInput{}
+	if err := writer.applyIfMatch(context.Background(), "GeoLite2-City.mmdb", input); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !requests.saw(http.MethodHead) {
+		t.Fatal("expected applyIfMatch to issue a HeadObject request")
+	}
+	if aws.ToString(input.IfNoneMatch) != "*" {
+		t.Errorf("IfNoneMatch = %q, want \"*\"", aws.ToString(input.IfNoneMatch))
+	}
+	if input.IfMatch != nil {
+		t.Errorf("IfMatch = %q, want unset", aws.ToString(input.IfMatch))
+	}
+}
@@ -0,0 +1,52 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// BlobStoreConfig bundles the per-backend connection and encryption details NewBlobStoreFromURL needs to
+// build whichever BlobStore the destination URL's scheme selects. Only the section matching the chosen
+// scheme is used.
+type BlobStoreConfig struct {
+	S3              S3Config
+	S3Encryption    EncryptionConfig
+	GCS             GCSConfig
+	GCSEncryption   GCSEncryptionConfig
+	Azure           AzureConfig
+	AzureEncryption AzureEncryptionConfig
+}
+
+// NewBlobStoreFromURL builds the BlobStore matching destinationURL's scheme:
+//
+//	s3://bucket/prefix        -> S3DatabaseWriter
+//	gs://bucket/prefix        -> GCSDatabaseWriter
+//	azblob://container/prefix -> AzureDatabaseWriter
+//
+// A "file:///var/lib/GeoIP" destination predates BlobStore and has no remote config to build, so it's
+// handled directly by the caller rather than through this constructor.
+func NewBlobStoreFromURL(ctx context.Context, destinationURL string, cfg BlobStoreConfig, verbose bool) (BlobStore, error) {
+	parsed, err := url.Parse(destinationURL)
+	if err != nil {
+		return nil, fmt.Errorf("parsing destination URL %q: %w", destinationURL, err)
+	}
+
+	bucket := parsed.Host
+	prefix := strings.Trim(parsed.Path, "/")
+
+	switch parsed.Scheme {
+	case "s3":
+		return NewS3DatabaseWriterFromConfig(ctx, cfg.S3, bucket, verbose, cfg.S3Encryption, WithPrefix(prefix))
+	case "gs":
+		return NewGCSDatabaseWriterFromConfig(ctx, cfg.GCS, bucket, verbose, cfg.GCSEncryption, WithGCSPrefix(prefix))
+	case "azblob":
+		return NewAzureDatabaseWriterFromConfig(cfg.Azure, bucket, verbose, cfg.AzureEncryption, WithAzurePrefix(prefix))
+	default:
+		return nil, fmt.Errorf(
+			"unsupported destination URL scheme %q in %q (expected s3, gs, or azblob)",
+			parsed.Scheme, destinationURL,
+		)
+	}
+}
@@ -0,0 +1,41 @@
+package database
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+func TestNewBlobStoreFromURL_RejectsUnsupportedScheme(t *testing.T) {
+	_, err := NewBlobStoreFromURL(context.Background(), "ftp://example.com/GeoIP", BlobStoreConfig{}, false)
+	if err == nil {
+		t.Fatal("expected an error for an unsupported scheme")
+	}
+}
+
+func TestNewBlobStoreFromURL_BuildsS3WriterWithBucketPrefixAndEncryption(t *testing.T) {
+	cfg := BlobStoreConfig{
+		S3:           S3Config{Region: "us-east-1"},
+		S3Encryption: EncryptionConfig{Mode: EncryptionKMS, KMSKeyID: "test-key"},
+	}
+
+	store, err := NewBlobStoreFromURL(context.Background(), "s3://test-bucket/GeoIP", cfg, false)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	writer, ok := store.(*S3DatabaseWriter)
+	if !ok {
+		t.Fatalf("store is a %T, want *S3DatabaseWriter", store)
+	}
+
+	if writer.s3Bucket != "test-bucket" {
+		t.Errorf("s3Bucket = %q, want %q", writer.s3Bucket, "test-bucket")
+	}
+	if writer.keyPrefix != "GeoIP" {
+		t.Errorf("keyPrefix = %q, want %q", writer.keyPrefix, "GeoIP")
+	}
+	if !reflect.DeepEqual(writer.encryption, cfg.S3Encryption) {
+		t.Errorf("encryption = %+v, want %+v (cfg.S3Encryption was not threaded through)", writer.encryption, cfg.S3Encryption)
+	}
+}